@@ -0,0 +1,50 @@
+// Package azblob registers the Azure Blob backup backend with the
+// types.BackupBackend registry, giving Azure Blob parity with S3. It
+// delegates the actual object storage operations to the vendored
+// github.com/longhorn/backupstore client, the same way backupstore/s3 does.
+package azblob
+
+import (
+	"os"
+
+	"github.com/longhorn/backupstore"
+
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+func init() {
+	types.RegisterBackupBackend(types.BackupStoreTypeAzureBlob, &backend{})
+}
+
+type backend struct {
+	destURL string
+}
+
+func (b *backend) Init(backupTargetURL string, credential types.CredentialSpec) error {
+	if err := types.ValidateBackupCredential(types.BackupStoreTypeAzureBlob, credential); err != nil {
+		return err
+	}
+	for k, v := range credential {
+		if err := os.Setenv(k, v); err != nil {
+			return err
+		}
+	}
+	b.destURL = backupTargetURL
+	return nil
+}
+
+func (b *backend) List(backupURL string) ([]string, error) {
+	return backupstore.List(backupURL)
+}
+
+func (b *backend) Read(backupURL string) ([]byte, error) {
+	return backupstore.Read(backupURL)
+}
+
+func (b *backend) Write(backupURL string, data []byte) error {
+	return backupstore.Write(backupURL, data)
+}
+
+func (b *backend) Delete(backupURL string) error {
+	return backupstore.Delete(backupURL)
+}
@@ -0,0 +1,43 @@
+// Package nfs registers the NFS backup backend with the
+// types.BackupBackend registry. It delegates the actual filesystem
+// operations to the vendored github.com/longhorn/backupstore client, the
+// same way backupstore/s3 does.
+package nfs
+
+import (
+	"github.com/longhorn/backupstore"
+
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+func init() {
+	types.RegisterBackupBackend(types.BackupStoreTypeNFS, &backend{})
+}
+
+type backend struct {
+	destURL string
+}
+
+func (b *backend) Init(backupTargetURL string, credential types.CredentialSpec) error {
+	if err := types.ValidateBackupCredential(types.BackupStoreTypeNFS, credential); err != nil {
+		return err
+	}
+	b.destURL = backupTargetURL
+	return nil
+}
+
+func (b *backend) List(backupURL string) ([]string, error) {
+	return backupstore.List(backupURL)
+}
+
+func (b *backend) Read(backupURL string) ([]byte, error) {
+	return backupstore.Read(backupURL)
+}
+
+func (b *backend) Write(backupURL string, data []byte) error {
+	return backupstore.Write(backupURL, data)
+}
+
+func (b *backend) Delete(backupURL string) error {
+	return backupstore.Delete(backupURL)
+}
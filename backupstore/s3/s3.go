@@ -0,0 +1,51 @@
+// Package s3 registers the S3 backup backend with the types.BackupBackend
+// registry. Like the azblob/gs/nfs/cifs backends under backupstore/, it
+// self-registers from its init(), which runs only when this package is
+// imported; these packages are wired in via blank import from
+// cmd/longhorn-manager.
+package s3
+
+import (
+	"os"
+
+	"github.com/longhorn/backupstore"
+
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+func init() {
+	types.RegisterBackupBackend(types.BackupStoreTypeS3, &backend{})
+}
+
+type backend struct {
+	destURL string
+}
+
+func (b *backend) Init(backupTargetURL string, credential types.CredentialSpec) error {
+	if err := types.ValidateBackupCredential(types.BackupStoreTypeS3, credential); err != nil {
+		return err
+	}
+	for k, v := range credential {
+		if err := os.Setenv(k, v); err != nil {
+			return err
+		}
+	}
+	b.destURL = backupTargetURL
+	return nil
+}
+
+func (b *backend) List(backupURL string) ([]string, error) {
+	return backupstore.List(backupURL)
+}
+
+func (b *backend) Read(backupURL string) ([]byte, error) {
+	return backupstore.Read(backupURL)
+}
+
+func (b *backend) Write(backupURL string, data []byte) error {
+	return backupstore.Write(backupURL, data)
+}
+
+func (b *backend) Delete(backupURL string) error {
+	return backupstore.Delete(backupURL)
+}
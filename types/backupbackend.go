@@ -0,0 +1,105 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CredentialSpec holds the backend-specific credential key/value pairs
+// configured on a BackupTarget CR. Its keys are backend-defined: an S3
+// backend expects AWSAccessKey/AWSSecretKey, an Azure Blob backend expects
+// an account name/key pair, and so on. This replaces the hardcoded
+// AWS-only env-plumbing that predates multi-backend support.
+type CredentialSpec map[string]string
+
+// BackupBackend is implemented by each supported backup store backend and
+// self-registered against the URL scheme it handles (e.g. "s3", "azblob",
+// "gs", "nfs", "cifs"). BackupStore operations are routed to the backend
+// registered for the scheme of the backup target URL.
+type BackupBackend interface {
+	// Init prepares the backend for use against the given backup URL using
+	// the provided credentials.
+	Init(backupTargetURL string, credential CredentialSpec) error
+	List(backupURL string) ([]string, error)
+	Read(backupURL string) ([]byte, error)
+	Write(backupURL string, data []byte) error
+	Delete(backupURL string) error
+}
+
+var (
+	backupBackendsMutex sync.RWMutex
+	backupBackends      = map[string]BackupBackend{}
+)
+
+// RegisterBackupBackend registers a BackupBackend implementation for the
+// given URL scheme (without "://"). It is expected to be called from the
+// init() function of each backend package, e.g.
+// github.com/longhorn/longhorn-manager/backupstore/s3, which must be
+// blank-imported by cmd/longhorn-manager for its registration to run.
+func RegisterBackupBackend(scheme string, backend BackupBackend) {
+	backupBackendsMutex.Lock()
+	defer backupBackendsMutex.Unlock()
+	backupBackends[scheme] = backend
+}
+
+// GetBackupBackend returns the BackupBackend registered for the scheme of
+// the given backup target URL, e.g. "s3://bucket@region/" resolves to the
+// backend registered under BackupStoreTypeS3.
+func GetBackupBackend(backupTargetURL string) (BackupBackend, error) {
+	scheme := GetBackupStoreType(backupTargetURL)
+	backupBackendsMutex.RLock()
+	defer backupBackendsMutex.RUnlock()
+	backend, ok := backupBackends[scheme]
+	if !ok {
+		return nil, fmt.Errorf("no backend registered for backup target scheme %v; is its backupstore/%v package blank-imported?", scheme, scheme)
+	}
+	return backend, nil
+}
+
+// GetBackupStoreType extracts the scheme portion (e.g. "s3", "azblob") of a
+// backup target URL such as "s3://bucket@region/".
+func GetBackupStoreType(backupTargetURL string) string {
+	parts := strings.SplitN(backupTargetURL, "://", 2)
+	return parts[0]
+}
+
+func ValidateBackupCredential(backendType string, credential CredentialSpec) error {
+	switch backendType {
+	case BackupStoreTypeS3:
+		if credential[AWSAccessKey] == "" || credential[AWSSecretKey] == "" {
+			return fmt.Errorf("both %v and %v are required for the %v backup backend", AWSAccessKey, AWSSecretKey, BackupStoreTypeS3)
+		}
+	case BackupStoreTypeAzureBlob:
+		if credential[AzureBlobAccountName] == "" || credential[AzureBlobAccountKey] == "" {
+			return fmt.Errorf("both %v and %v are required for the %v backup backend", AzureBlobAccountName, AzureBlobAccountKey, BackupStoreTypeAzureBlob)
+		}
+	case BackupStoreTypeGCS:
+		if credential[GCSServiceAccountCredential] == "" {
+			return fmt.Errorf("%v is required for the %v backup backend", GCSServiceAccountCredential, BackupStoreTypeGCS)
+		}
+	case BackupStoreTypeNFS:
+		// NFS is reachable via the cluster network and doesn't require
+		// object-storage style credentials.
+		return nil
+	case BackupStoreTypeCIFS:
+		if credential[CIFSUsername] == "" || credential[CIFSPassword] == "" {
+			return fmt.Errorf("both %v and %v are required for the %v backup backend", CIFSUsername, CIFSPassword, BackupStoreTypeCIFS)
+		}
+	default:
+		return fmt.Errorf("unsupported backup backend type: %v", backendType)
+	}
+	return nil
+}
+
+const (
+	AzureBlobAccountName = "AZBLOB_ACCOUNT_NAME"
+	AzureBlobAccountKey  = "AZBLOB_ACCOUNT_KEY"
+	AzureBlobEndpoint    = "AZBLOB_ENDPOINT"
+	AzureBlobCert        = "AZBLOB_CERT"
+
+	GCSServiceAccountCredential = "GCS_SERVICE_ACCOUNT_CREDENTIAL"
+
+	CIFSUsername = "CIFS_USERNAME"
+	CIFSPassword = "CIFS_PASSWORD"
+)
@@ -0,0 +1,23 @@
+package types
+
+// DiskSpec is the user-configurable part of a disk entry in
+// Node.Spec.Disks, keyed by disk name.
+type DiskSpec struct {
+	// Path is the filesystem path on the node hosting the disk.
+	Path string `json:"path"`
+	// AllowScheduling controls whether new replicas can be scheduled onto
+	// this disk.
+	AllowScheduling bool `json:"allowScheduling"`
+	// EvictionRequested marks the disk for replica eviction ahead of
+	// removal.
+	EvictionRequested bool `json:"evictionRequested"`
+	// StorageReserved is the number of bytes reserved on the disk for
+	// non-Longhorn use.
+	StorageReserved int64 `json:"storageReserved"`
+	// Tags are arbitrary labels used by the replica scheduler's disk
+	// selector.
+	Tags []string `json:"tags"`
+	// Encryption configures encryption-at-rest for the replica data
+	// directories and backing images stored on this disk.
+	Encryption EncryptionSpec `json:"encryption"`
+}
@@ -81,6 +81,12 @@ const (
 	KubernetesTopologyRegionLabelKey      = "topology.kubernetes.io/region"
 	KubernetesTopologyZoneLabelKey        = "topology.kubernetes.io/zone"
 
+	// SettingNameReplicaTopologyKeys is the name of the setting holding the
+	// ordered, comma-separated node label priority list the replica
+	// scheduler uses for anti-affinity. Each entry is a full node label key,
+	// e.g. "topology.kubernetes.io/region,topology.kubernetes.io/zone,rack".
+	SettingNameReplicaTopologyKeys = "replica-topology-keys"
+
 	LonghornDriverName = "driver.longhorn.io"
 
 	DefaultDiskPrefix = "default-disk-"
@@ -112,7 +118,11 @@ const (
 	EnvPodIP          = "POD_IP"
 	EnvServiceAccount = "SERVICE_ACCOUNT"
 
-	BackupStoreTypeS3 = "s3"
+	BackupStoreTypeS3        = "s3"
+	BackupStoreTypeAzureBlob = "azblob"
+	BackupStoreTypeGCS       = "gs"
+	BackupStoreTypeNFS       = "nfs"
+	BackupStoreTypeCIFS      = "cifs"
 
 	AWSIAMRoleAnnotation = "iam.amazonaws.com/role"
 	AWSIAMRoleArn        = "AWS_IAM_ROLE_ARN"
@@ -134,6 +144,15 @@ const (
 	OptionFrontend            = "frontend"
 	OptionDiskSelector        = "diskSelector"
 	OptionNodeSelector        = "nodeSelector"
+	OptionEncryption          = "encryption"
+	OptionEncryptionSecret    = "encryptionSecret"
+	OptionCloneFromPVC        = "cloneFromPVC"
+	OptionSourceNamespace     = "sourceNamespace"
+
+	// CSIEncryptionPassphraseKey is the key used to plumb the LUKS passphrase
+	// secret referenced by OptionEncryptionSecret through the CSI provisioner
+	// into the NodePublishVolume/NodeStageVolume secrets map.
+	CSIEncryptionPassphraseKey = "CRYPTO_KEY_VALUE"
 
 	// DefaultStaleReplicaTimeout in minutes. 48h by default
 	DefaultStaleReplicaTimeout = "2880"
@@ -184,6 +203,17 @@ func GetCronJobNameForVolumeAndJob(vName, job string) string {
 	return vName + "-" + job + recurringSuffix
 }
 
+// ValidateRecurringJobName rejects a job name longer than MaximumJobNameSize,
+// the room left for the job name once the volume name and the recurring/pod
+// suffixes are counted. It returns an error rather than shortening the name;
+// GetCronJobNameForVolumeAndJob itself never truncates.
+func ValidateRecurringJobName(name string) error {
+	if len(name) > MaximumJobNameSize {
+		return fmt.Errorf("recurring job name %v is longer than the maximum allowed length %v", name, MaximumJobNameSize)
+	}
+	return nil
+}
+
 func GetAPIServerAddressFromIP(ip string) string {
 	return net.JoinHostPort(ip, strconv.Itoa(DefaultAPIPort))
 }
@@ -228,12 +258,17 @@ func GetBackingImageManagerDirectoryOnHost(diskPath string) string {
 	return filepath.Join(diskPath, BackingImagesManagerDirectory)
 }
 
-func GetBackingImageDirectoryOnHost(diskPath, backingImageName, backingImageUUID string) string {
-	return filepath.Join(GetBackingImageManagerDirectoryOnHost(diskPath), GetBackingImageDirectoryName(backingImageName, backingImageUUID))
+// GetBackingImageDirectoryOnHost returns the directory a backing image is
+// stored under. When encryption is enabled for the disk, the backing image
+// is stored on the disk's LUKS-mapped device rather than its raw path, so
+// the manager must map the device (see GetEncryptedDiskDeviceMapperPath)
+// before this directory can be created.
+func GetBackingImageDirectoryOnHost(diskPath, diskUUID, backingImageName, backingImageUUID string, encryption EncryptionSpec) string {
+	return filepath.Join(GetBackingImageManagerDirectoryOnHost(GetEncryptionAwareDiskPath(diskPath, diskUUID, encryption)), GetBackingImageDirectoryName(backingImageName, backingImageUUID))
 }
 
-func GetBackingImagePathForReplicaManagerContainer(diskPath, backingImageName, backingImageUUID string) string {
-	return filepath.Join(ReplicaHostPrefix, GetBackingImageDirectoryOnHost(diskPath, backingImageName, backingImageUUID), BackingImageFileName)
+func GetBackingImagePathForReplicaManagerContainer(diskPath, diskUUID, backingImageName, backingImageUUID string, encryption EncryptionSpec) string {
+	return filepath.Join(ReplicaHostPrefix, GetBackingImageDirectoryOnHost(diskPath, diskUUID, backingImageName, backingImageUUID, encryption), BackingImageFileName)
 }
 
 var (
@@ -334,6 +369,53 @@ func GetCronJobPodLabels(volumeName string, job *RecurringJob) map[string]string
 	return labels
 }
 
+const (
+	DefaultRecurringJobConcurrency                = 0
+	DefaultRecurringJobSuccessfulJobsHistoryLimit = 1
+	DefaultRecurringJobFailedJobsHistoryLimit     = 1
+	DefaultRecurringJobBackoffLimit               = 3
+)
+
+// RecurringJobPolicy governs how the manager's leader-elected scheduler
+// gates and retains recurring jobs across the whole cluster, rather than
+// leaving every per-volume CronJob to run independently.
+type RecurringJobPolicy struct {
+	// Concurrency is the maximum number of jobs with the same name allowed
+	// to run simultaneously across all volumes. Zero means unlimited.
+	Concurrency int `json:"concurrency"`
+	// SuccessfulJobsHistoryLimit is the number of completed Jobs to retain
+	// per CronJob, mirroring batchv1.CronJobSpec.
+	SuccessfulJobsHistoryLimit int `json:"successfulJobsHistoryLimit"`
+	// FailedJobsHistoryLimit is the number of failed Jobs to retain per
+	// CronJob, mirroring batchv1.CronJobSpec.
+	FailedJobsHistoryLimit int `json:"failedJobsHistoryLimit"`
+	// BackoffLimit is the number of retries before a job run is marked
+	// failed, mirroring batchv1.JobSpec.
+	BackoffLimit int `json:"backoffLimit"`
+}
+
+func ValidateRecurringJobPolicy(policy *RecurringJobPolicy) error {
+	if policy.Concurrency < 0 {
+		return fmt.Errorf("recurring job concurrency must not be negative, got %v", policy.Concurrency)
+	}
+	if policy.SuccessfulJobsHistoryLimit < 0 {
+		return fmt.Errorf("recurring job successfulJobsHistoryLimit must not be negative, got %v", policy.SuccessfulJobsHistoryLimit)
+	}
+	if policy.FailedJobsHistoryLimit < 0 {
+		return fmt.Errorf("recurring job failedJobsHistoryLimit must not be negative, got %v", policy.FailedJobsHistoryLimit)
+	}
+	if policy.BackoffLimit < 0 {
+		return fmt.Errorf("recurring job backoffLimit must not be negative, got %v", policy.BackoffLimit)
+	}
+	return nil
+}
+
+// GetRecurringJobSemaphoreKey returns the key the leader-elected scheduler
+// uses to gate concurrent starts of the same recurring job across volumes.
+func GetRecurringJobSemaphoreKey(jobName string) string {
+	return RecurringJobLabel + "-" + jobName
+}
+
 func GetBackingImageLabels() map[string]string {
 	labels := GetBaseLabelsForSystemManagedComponent()
 	labels[GetLonghornLabelComponentKey()] = LonghornLabelBackingImage
@@ -394,6 +476,57 @@ func GetRegionAndZone(labels map[string]string, isUsingTopologyLabels bool) (str
 	return region, zone
 }
 
+// TopologyDomain is one level of a node's topology, e.g. the "zone" level
+// with value "us-east-1a".
+type TopologyDomain struct {
+	Key   string
+	Value string
+}
+
+// DefaultReplicaTopologyKeys is used when the ReplicaTopologyKeys setting is
+// unset. It matches the region/zone levels GetRegionAndZone reads when
+// isUsingTopologyLabels is true, ordered from the most significant domain to
+// the least; the failure-domain.beta.kubernetes.io labels are not read.
+var DefaultReplicaTopologyKeys = []string{
+	KubernetesTopologyRegionLabelKey,
+	KubernetesTopologyZoneLabelKey,
+}
+
+// GetTopologyDomains returns the node's topology domains ordered from the
+// highest-diversity level to the lowest, according to topologyKeys (the
+// parsed value of the ReplicaTopologyKeys setting). Unlike GetRegionAndZone,
+// which only understands two hardcoded levels, this allows an arbitrary
+// label priority list such as region, zone, rack, hypervisor, host so the
+// replica scheduler can spread replicas across on-prem failure domains like
+// racks and power domains.
+func GetTopologyDomains(labels map[string]string, topologyKeys []string) []TopologyDomain {
+	domains := make([]TopologyDomain, 0, len(topologyKeys))
+	for _, key := range topologyKeys {
+		if v, ok := labels[key]; ok && v != "" {
+			domains = append(domains, TopologyDomain{Key: key, Value: v})
+		}
+	}
+	return domains
+}
+
+// ParseReplicaTopologyKeys splits the comma-separated ReplicaTopologyKeys
+// setting value into an ordered label priority list, e.g.
+// "topology.kubernetes.io/region,topology.kubernetes.io/zone,rack".
+func ParseReplicaTopologyKeys(setting string) []string {
+	if strings.TrimSpace(setting) == "" {
+		return DefaultReplicaTopologyKeys
+	}
+	keys := strings.Split(setting, ",")
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
 func GetEngineImageChecksumName(image string) string {
 	return engineImagePrefix + util.GetStringChecksum(strings.TrimSpace(image))[:ImageChecksumNameLength]
 }
@@ -439,8 +572,39 @@ func GetInstanceManagerPrefix(imType InstanceManagerType) string {
 	return ""
 }
 
-func GetReplicaDataPath(diskPath, dataDirectoryName string) string {
-	return filepath.Join(diskPath, "replicas", dataDirectoryName)
+// GetReplicaDataPath returns the directory a replica's data is stored under.
+// When encryption is enabled on the disk, the replica subdirectory is
+// created on the disk's LUKS-mapped device rather than its raw path; the
+// manager must map the device (see GetEncryptedDiskDeviceMapperPath) before
+// this path can be created.
+func GetReplicaDataPath(diskPath, diskUUID, dataDirectoryName string, encryption EncryptionSpec) string {
+	return filepath.Join(GetEncryptionAwareDiskPath(diskPath, diskUUID, encryption), "replicas", dataDirectoryName)
+}
+
+// GetEncryptedDiskDeviceMapperName returns the name under which a LUKS disk
+// path is mapped by dm-crypt before the replica subdirectory is created on
+// it. longhorn-manager maps the device at this name, then creates the
+// replica data path as usual once the mapped block device is formatted.
+func GetEncryptedDiskDeviceMapperName(diskUUID string) string {
+	return "luks-" + diskUUID
+}
+
+func GetEncryptedDiskDeviceMapperPath(diskUUID string) string {
+	return filepath.Join("/dev/mapper", GetEncryptedDiskDeviceMapperName(diskUUID))
+}
+
+// GetEncryptionAwareDiskPath returns the path replica data and backing
+// images should actually be written under: the disk's LUKS-mapped device
+// path when encryption is enabled, or diskPath unchanged otherwise.
+func GetEncryptionAwareDiskPath(diskPath, diskUUID string, encryption EncryptionSpec) string {
+	mode := encryption.Mode
+	if mode == "" {
+		mode = EncryptionModeNone
+	}
+	if mode == EncryptionModeNone {
+		return diskPath
+	}
+	return GetEncryptedDiskDeviceMapperPath(diskUUID)
 }
 
 func GetReplicaMountedDataPath(dataPath string) string {
@@ -491,6 +655,98 @@ func ValidateAccessMode(mode AccessMode) error {
 	return nil
 }
 
+// EncryptionMode describes how a disk or volume's replica data directory is
+// protected at rest.
+type EncryptionMode string
+
+const (
+	// EncryptionModeNone leaves the replica data directory unencrypted. This
+	// is the default and preserves existing behavior.
+	EncryptionModeNone = EncryptionMode("none")
+	// EncryptionModeLUKSPassphrase maps the disk path through dm-crypt/LUKS
+	// using a passphrase sourced from a Kubernetes Secret.
+	EncryptionModeLUKSPassphrase = EncryptionMode("luks-passphrase")
+	// EncryptionModeKMS maps the disk path through dm-crypt/LUKS using a key
+	// fetched from an external KMS URL rather than a Kubernetes Secret.
+	EncryptionModeKMS = EncryptionMode("kms")
+)
+
+// EncryptionSpec is used as the Encryption field of DiskSpec and VolumeSpec to describe the
+// encryption-at-rest configuration for the replica data directories and
+// backing images stored on a disk or belonging to a volume.
+type EncryptionSpec struct {
+	// Mode is one of EncryptionModeNone, EncryptionModeLUKSPassphrase, or
+	// EncryptionModeKMS.
+	Mode EncryptionMode `json:"mode"`
+	// SecretRef is the name of the Kubernetes Secret holding the LUKS
+	// passphrase. Only meaningful when Mode is EncryptionModeLUKSPassphrase.
+	SecretRef string `json:"secretRef,omitempty"`
+	// SecretNamespace is the namespace of SecretRef.
+	SecretNamespace string `json:"secretNamespace,omitempty"`
+	// KMSURL identifies the external KMS endpoint to fetch the key from. Only
+	// meaningful when Mode is EncryptionModeKMS.
+	KMSURL string `json:"kmsURL,omitempty"`
+	// KeyHandle is the opaque identifier of the derived key as reported by
+	// the engine after the LUKS device was mapped. It is populated by
+	// longhorn-manager and is not user-settable.
+	KeyHandle string `json:"keyHandle,omitempty"`
+}
+
+// ValidateEncryptionMode normalizes the zero value "" (an unset
+// EncryptionSpec.Mode, the common case for a disk/volume created without an
+// explicit encryption block) to EncryptionModeNone before validating.
+func ValidateEncryptionMode(mode EncryptionMode) error {
+	if mode == "" {
+		mode = EncryptionModeNone
+	}
+	switch mode {
+	case EncryptionModeNone, EncryptionModeLUKSPassphrase, EncryptionModeKMS:
+		return nil
+	default:
+		return fmt.Errorf("invalid encryption mode: %v", mode)
+	}
+}
+
+// CloneStatus is the CloneStatus field of VolumeStatus; it tracks the progress of initializing a
+// new volume by streaming replicas from an existing volume's snapshot, the
+// same way RestoreStatus tracks restore-from-backup progress.
+type CloneStatus struct {
+	// SourceVolumeName is the volume the clone is streaming replicas from.
+	SourceVolumeName string `json:"sourceVolumeName,omitempty"`
+	// SourceVolumeNamespace is the namespace of SourceVolumeName. Empty
+	// means the same namespace as the cloned volume.
+	SourceVolumeNamespace string `json:"sourceVolumeNamespace,omitempty"`
+	// Snapshot is the name of the source volume's snapshot the clone is
+	// initialized from.
+	Snapshot string `json:"snapshot,omitempty"`
+	// State tracks the clone's lifecycle, e.g. "initiated", "in-progress",
+	// "completed", "failed".
+	State string `json:"state,omitempty"`
+	// Progress is an integer percentage in the range 0-100.
+	Progress int `json:"progress"`
+}
+
+// ValidateCloneSource ensures a CSI CreateVolume request names at most one
+// clone source: OptionCloneFromPVC and OptionFromBackup are mutually
+// exclusive since a volume can only be initialized one way, but naming
+// neither is valid too — it just means a fresh, empty volume.
+func ValidateCloneSource(cloneFromPVC, fromBackup string) error {
+	if cloneFromPVC != "" && fromBackup != "" {
+		return fmt.Errorf("%v and %v are mutually exclusive", OptionCloneFromPVC, OptionFromBackup)
+	}
+	return nil
+}
+
+// ValidateSourceNamespace ensures OptionSourceNamespace is only set
+// alongside a clone source, since it is meaningless for a fresh volume or a
+// restore-from-backup.
+func ValidateSourceNamespace(sourceNamespace, cloneFromPVC string) error {
+	if sourceNamespace != "" && cloneFromPVC == "" {
+		return fmt.Errorf("%v requires %v to be set", OptionSourceNamespace, OptionCloneFromPVC)
+	}
+	return nil
+}
+
 func GetDaemonSetNameFromEngineImageName(engineImageName string) string {
 	return "engine-image-" + engineImageName
 }
@@ -615,6 +871,7 @@ func CreateDefaultDisk(dataPath string) (map[string]DiskSpec, error) {
 			AllowScheduling:   true,
 			EvictionRequested: false,
 			StorageReserved:   diskInfo.StorageMaximum * 30 / 100,
+			Encryption:        EncryptionSpec{Mode: EncryptionModeNone},
 		},
 	}, nil
 }
@@ -0,0 +1,41 @@
+package types
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VolumeSpec is the user-configurable part of a Longhorn Volume CR.
+type VolumeSpec struct {
+	NumberOfReplicas    int                `json:"numberOfReplicas"`
+	StaleReplicaTimeout int                `json:"staleReplicaTimeout"`
+	BaseImage           string             `json:"baseImage"`
+	Frontend            string             `json:"frontend"`
+	DiskSelector        []string           `json:"diskSelector"`
+	NodeSelector        []string           `json:"nodeSelector"`
+	DataLocality        DataLocality       `json:"dataLocality"`
+	AccessMode          AccessMode         `json:"accessMode"`
+	ReplicaAutoBalance  ReplicaAutoBalance `json:"replicaAutoBalance"`
+	// Encryption configures encryption-at-rest for this volume's replica
+	// data directories, mirroring DiskSpec.Encryption for disk-level
+	// defaults. A non-none value here overrides the disk default.
+	Encryption EncryptionSpec `json:"encryption"`
+}
+
+// VolumeStatus is the manager-reported state of a Longhorn Volume CR.
+type VolumeStatus struct {
+	State      string `json:"state"`
+	Robustness string `json:"robustness"`
+	// CloneStatus tracks progress when this volume was initialized by
+	// streaming replicas from another volume's snapshot, so the UI can
+	// render clone progress the same way it does for restore-from-backup.
+	CloneStatus CloneStatus `json:"cloneStatus"`
+}
+
+// Volume is the Longhorn Volume CR.
+type Volume struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeSpec   `json:"spec,omitempty"`
+	Status VolumeStatus `json:"status,omitempty"`
+}